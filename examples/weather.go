@@ -0,0 +1,60 @@
+// Package examples holds sample mcp.ToolHandler implementations that
+// consumers of the mcp package can register as a starting point, without the
+// library itself hard-coding any particular tool.
+package examples
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rob/go-web-server/mcp"
+)
+
+// WeatherArgs represents the input arguments for the get_weather tool.
+type WeatherArgs struct {
+	Location string `json:"location"`
+}
+
+// WeatherTool is a sample mcp.ToolHandler that returns canned weather data.
+type WeatherTool struct{}
+
+// Definition implements mcp.ToolHandler.
+func (WeatherTool) Definition() mcp.Tool {
+	return mcp.Tool{
+		Name:        "get_weather",
+		Description: "Get current weather information for a location",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"location": {
+					"type": "string",
+					"description": "City name or zip code"
+				}
+			},
+			"required": ["location"]
+		}`),
+	}
+}
+
+// Invoke implements mcp.ToolHandler.
+func (WeatherTool) Invoke(ctx context.Context, args json.RawMessage) (mcp.ToolResult, error) {
+	var weatherArgs WeatherArgs
+	if err := json.Unmarshal(args, &weatherArgs); err != nil {
+		return mcp.ToolResult{
+			Content: []mcp.ContentItem{
+				{Type: "text", Text: "Failed to parse weather arguments"},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return mcp.ToolResult{
+		Content: []mcp.ContentItem{
+			{
+				Type: "text",
+				Text: fmt.Sprintf("Current weather in %s:\nTemperature: 72°F\nConditions: Partly cloudy", weatherArgs.Location),
+			},
+		},
+	}, nil
+}
@@ -1,12 +1,12 @@
 package main
 
 import (
-	"crypto/tls"
 	"fmt"
 	"log"
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/rob/go-web-server/examples"
 	"github.com/rob/go-web-server/mcp"
 )
 
@@ -15,40 +15,32 @@ func main() {
 
 	// Create and configure MCP server
 	mcpServer := mcp.NewServer()
-
-	// MCP endpoint with authentication middleware
+	mcpServer.RegisterTool(examples.WeatherTool{})
+	mcpServer.WithAuthenticators(&mcp.APIKeyAuthenticator{
+		Keys: map[string]string{
+			"dev-key": "local-dev",
+		},
+	})
+	mcpServer.WithTLS(&mcp.TLSConfig{
+		CertFile:       "cert.pem",
+		KeyFile:        "key.pem",
+		ClientAuthType: "none",
+	})
+
+	// MCP endpoint with access logging and authentication middleware
 	// New MCP endpoint
-	router.HandleFunc("/mcp", mcpServer.AuthMiddleware(mcpServer.HandleMCP))
+	router.HandleFunc("/mcp", mcpServer.AccessLogMiddleware(mcpServer.AuthMiddleware(mcpServer.HandleMCP)))
 
 	// Legacy SSE endpoint for backward compatibility
-	router.HandleFunc("/sse", mcpServer.AuthMiddleware(mcpServer.HandleLegacySSE))
+	router.HandleFunc("/sse", mcpServer.AccessLogMiddleware(mcpServer.AuthMiddleware(mcpServer.HandleLegacySSE)))
 
 	// Regular web server endpoints
 	router.HandleFunc("/", handleHome)
 	router.HandleFunc("/health", handleHealth)
 
-	// Start HTTP server
-	go func() {
-		log.Printf("HTTP server starting on port 8080...\n")
-		if err := http.ListenAndServe(":8080", router); err != nil {
-			log.Printf("HTTP server error: %v\n", err)
-		}
-	}()
-
-	// Start HTTPS server
-	tlsConfig := &tls.Config{
-		MinVersion: tls.VersionTLS12,
-	}
-
-	httpsServer := &http.Server{
-		Addr:      ":8443",
-		Handler:   router,
-		TLSConfig: tlsConfig,
-	}
-
-	log.Printf("HTTPS server starting on port 8443...\n")
-	if err := httpsServer.ListenAndServeTLS("cert.pem", "key.pem"); err != nil {
-		log.Fatalf("HTTPS server error: %v", err)
+	log.Printf("Starting MCP server on :8080 (HTTP) and :8443 (HTTPS)...\n")
+	if err := mcpServer.ListenAndServe(":8080", ":8443", router); err != nil {
+		log.Fatalf("server error: %v", err)
 	}
 }
 
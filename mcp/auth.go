@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Principal identifies whoever AuthMiddleware authenticated a request as.
+type Principal struct {
+	Subject string
+	Method  string // "api-key", "bearer-jwt", "mtls"
+}
+
+type principalContextKey struct{}
+
+func withPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+// PrincipalFromContext returns the Principal AuthMiddleware attached to ctx,
+// if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(Principal)
+	return p, ok
+}
+
+// Authenticator authenticates an incoming HTTP request, returning the
+// resulting Principal or an error describing why it was rejected.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// WithAuthenticators configures the chain of Authenticators that
+// AuthMiddleware consults, in order, until one succeeds. With none
+// configured, AuthMiddleware falls back to the legacy X-API-Key check.
+func (s *Server) WithAuthenticators(authenticators ...Authenticator) *Server {
+	s.authenticators = authenticators
+	return s
+}
+
+// AuthMiddleware authenticates the request via the configured Authenticator
+// chain, attaches the resulting Principal and resolved client IP to the
+// request context, and rejects with a JSON-RPC -32001 error if every
+// authenticator fails.
+func (s *Server) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, err := s.authenticate(r)
+		if err != nil {
+			s.sendJSONRPCError(w, http.StatusUnauthorized, &ErrorResponse{
+				Code:    -32001,
+				Message: "Unauthorized: " + err.Error(),
+			})
+			return
+		}
+
+		ctx := withClientIP(r.Context(), s.clientIP(r))
+		ctx = withPrincipal(ctx, principal)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// authenticate runs the configured Authenticator chain and returns the first
+// Principal to succeed.
+func (s *Server) authenticate(r *http.Request) (Principal, error) {
+	if len(s.authenticators) == 0 {
+		token := r.Header.Get("X-API-Key")
+		if !s.validateToken(token) {
+			return Principal{}, errors.New("invalid API key")
+		}
+		return Principal{Subject: token, Method: "api-key"}, nil
+	}
+
+	var lastErr error
+	for _, a := range s.authenticators {
+		principal, err := a.Authenticate(r)
+		if err == nil {
+			return principal, nil
+		}
+		lastErr = err
+	}
+	return Principal{}, lastErr
+}
+
+// validateToken is the legacy fallback used when no Authenticator chain has
+// been configured via WithAuthenticators. It only checks that a key was
+// supplied; callers wanting real verification should configure an
+// APIKeyAuthenticator (or another Authenticator) via WithAuthenticators
+// instead of relying on this fallback.
+func (s *Server) validateToken(token string) bool {
+	return token != ""
+}
+
+// APIKeyAuthenticator authenticates requests by looking up the X-API-Key
+// header in a static table of keys to principal subjects.
+type APIKeyAuthenticator struct {
+	Keys map[string]string
+}
+
+// Authenticate implements Authenticator.
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		return Principal{}, errors.New("missing X-API-Key")
+	}
+	subject, ok := a.Keys[key]
+	if !ok {
+		return Principal{}, errors.New("invalid API key")
+	}
+	return Principal{Subject: subject, Method: "api-key"}, nil
+}
+
+// BearerJWTAuthenticator authenticates requests bearing an
+// "Authorization: Bearer <token>" header signed with HS256 and Secret. It
+// only checks the signature, the "exp" claim, and that "sub" is present;
+// consumers needing richer claim validation should implement their own
+// Authenticator.
+type BearerJWTAuthenticator struct {
+	Secret []byte
+}
+
+// Authenticate implements Authenticator.
+func (a *BearerJWTAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, prefix) {
+		return Principal{}, errors.New("missing bearer token")
+	}
+
+	claims, err := verifyHS256JWT(strings.TrimPrefix(header, prefix), a.Secret)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return Principal{}, errors.New("token missing sub claim")
+	}
+	return Principal{Subject: sub, Method: "bearer-jwt"}, nil
+}
+
+// verifyHS256JWT checks the signature and expiry of a compact HS256 JWT and
+// returns its claims.
+func verifyHS256JWT(token string, secret []byte) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("malformed signature")
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, errors.New("invalid signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("malformed claims")
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errors.New("malformed claims")
+	}
+
+	if exp, ok := claims["exp"].(float64); ok && float64(time.Now().Unix()) > exp {
+		return nil, errors.New("token expired")
+	}
+
+	return claims, nil
+}
+
+// MTLSAuthenticator authenticates requests by the common name on the
+// client certificate presented during the TLS handshake. It only applies
+// when the listener is configured (via TLSConfig.ClientAuthType) to request
+// or require client certificates.
+type MTLSAuthenticator struct{}
+
+// Authenticate implements Authenticator.
+func (MTLSAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return Principal{}, errors.New("no client certificate presented")
+	}
+	cn := r.TLS.PeerCertificates[0].Subject.CommonName
+	if cn == "" {
+		return Principal{}, errors.New("client certificate missing CN")
+	}
+	return Principal{Subject: cn, Method: "mtls"}, nil
+}
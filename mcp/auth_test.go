@@ -0,0 +1,24 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAuthenticateLegacyFallbackRejectsMissingKey covers the
+// no-Authenticators-configured path: it must reject requests without an
+// X-API-Key rather than accepting everyone.
+func TestAuthenticateLegacyFallbackRejectsMissingKey(t *testing.T) {
+	s := NewServer()
+
+	r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	if _, err := s.authenticate(r); err == nil {
+		t.Fatalf("expected missing X-API-Key to be rejected")
+	}
+
+	r.Header.Set("X-API-Key", "some-key")
+	if _, err := s.authenticate(r); err != nil {
+		t.Fatalf("expected non-empty X-API-Key to be accepted, got %v", err)
+	}
+}
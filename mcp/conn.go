@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Conn is a full-duplex JSON-RPC 2.0 connection bound to a single session. In
+// addition to the server answering client requests, it lets the server issue
+// its own requests to the client (e.g. sampling/createMessage, roots/list)
+// and correlate the eventual response, and lets incoming cancellations reach
+// the handler actually doing the work.
+type Conn struct {
+	session *Session
+
+	seq int64 // atomic counter for server-originated request IDs
+
+	pendingMu sync.Mutex
+	pending   map[string]chan *Response
+
+	handlingMu sync.Mutex
+	handling   map[string]context.CancelFunc
+}
+
+// newConn creates a Conn bound to the given session.
+func newConn(session *Session) *Conn {
+	return &Conn{
+		session:  session,
+		pending:  make(map[string]chan *Response),
+		handling: make(map[string]context.CancelFunc),
+	}
+}
+
+// nextID allocates a server-originated request ID. The "s-" prefix keeps
+// server IDs from colliding with client-generated ones.
+func (c *Conn) nextID() string {
+	n := atomic.AddInt64(&c.seq, 1)
+	return fmt.Sprintf("s-%d", n)
+}
+
+// Call sends a server-initiated JSON-RPC request to the client over the
+// session's SSE stream and blocks until a matching response arrives or ctx
+// is done. On cancellation it notifies the client with
+// notifications/cancelled before returning ctx.Err().
+func (c *Conn) Call(ctx context.Context, method string, params interface{}) (*json.RawMessage, error) {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("marshal params: %w", err)
+	}
+
+	id := c.nextID()
+	respCh := make(chan *Response, 1)
+
+	c.pendingMu.Lock()
+	c.pending[id] = respCh
+	c.pendingMu.Unlock()
+
+	defer func() {
+		c.pendingMu.Lock()
+		delete(c.pending, id)
+		c.pendingMu.Unlock()
+	}()
+
+	c.send(Request{
+		JSONRPC: JSONRPC{Version: "2.0", ID: id},
+		Method:  method,
+		Params:  rawParams,
+	})
+
+	select {
+	case resp := <-respCh:
+		if resp.Error != nil {
+			return nil, fmt.Errorf("%s: %s", method, resp.Error.Message)
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		c.Notify("notifications/cancelled", CancellationParams{RequestID: id, Reason: ctx.Err().Error()})
+		return nil, ctx.Err()
+	}
+}
+
+// Notify sends a one-way JSON-RPC notification to the client.
+func (c *Conn) Notify(method string, params interface{}) {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+	c.send(Notification{
+		Version: "2.0",
+		Method:  method,
+		Params:  rawParams,
+	})
+}
+
+// send pushes a message onto every SSE channel currently registered for the
+// session, reusing the streams the GET handler already maintains rather than
+// opening a second one. It goes through Session.broadcast so it shares the
+// same lock as the GET handler's register/unregister of MessageChannels.
+func (c *Conn) send(message interface{}) {
+	c.session.broadcast(message)
+}
+
+// resolve delivers a client response to the Call waiting on its ID. It
+// reports whether a pending call was found.
+func (c *Conn) resolve(resp *Response) bool {
+	id, ok := idString(resp.ID)
+	if !ok {
+		return false
+	}
+
+	c.pendingMu.Lock()
+	ch, exists := c.pending[id]
+	if exists {
+		delete(c.pending, id)
+	}
+	c.pendingMu.Unlock()
+
+	if !exists {
+		return false
+	}
+	ch <- resp
+	return true
+}
+
+// trackHandling registers the cancel func for an in-flight incoming request
+// so a later notifications/cancelled can stop it.
+func (c *Conn) trackHandling(id string, cancel context.CancelFunc) {
+	c.handlingMu.Lock()
+	c.handling[id] = cancel
+	c.handlingMu.Unlock()
+}
+
+// stopHandling removes a completed request's cancel func.
+func (c *Conn) stopHandling(id string) {
+	c.handlingMu.Lock()
+	delete(c.handling, id)
+	c.handlingMu.Unlock()
+}
+
+// cancelHandling cancels an in-flight incoming request by ID, if one is
+// still running. It reports whether anything was cancelled.
+func (c *Conn) cancelHandling(id string) bool {
+	c.handlingMu.Lock()
+	cancel, exists := c.handling[id]
+	if exists {
+		delete(c.handling, id)
+	}
+	c.handlingMu.Unlock()
+
+	if !exists {
+		return false
+	}
+	cancel()
+	return true
+}
+
+// close drains every pending Call with an error and cancels every in-flight
+// incoming request. Called when the owning session is torn down.
+func (c *Conn) close() {
+	c.pendingMu.Lock()
+	for id, ch := range c.pending {
+		ch <- &Response{
+			JSONRPC: JSONRPC{Version: "2.0", ID: id},
+			Error:   &ErrorResponse{Code: -32000, Message: "session closed"},
+		}
+		delete(c.pending, id)
+	}
+	c.pendingMu.Unlock()
+
+	c.handlingMu.Lock()
+	for id, cancel := range c.handling {
+		cancel()
+		delete(c.handling, id)
+	}
+	c.handlingMu.Unlock()
+}
+
+// idString normalizes a JSON-RPC ID to a string for map lookups. IDs come
+// back from encoding/json's untyped interface{} decoding as either string or
+// float64.
+func idString(id any) (string, bool) {
+	switch v := id.(type) {
+	case string:
+		return v, true
+	case float64:
+		return fmt.Sprintf("%v", v), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestConnSendConcurrentWithChannelRegistration exercises Conn.send racing
+// against the GET handler's register/unregister of MessageChannels. Run with
+// -race: before Session grew its own channelsMu, this tripped "WARNING: DATA
+// RACE" on the MessageChannels slice header.
+func TestConnSendConcurrentWithChannelRegistration(t *testing.T) {
+	session := &Session{ID: "test-session"}
+	conn := newConn(session)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			ch := make(chan interface{}, 1)
+			session.addMessageChannel(ch)
+			session.removeMessageChannel(ch)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			conn.send(Notification{Version: "2.0", Method: "ping"})
+		}
+	}()
+
+	wg.Wait()
+}
+
+// TestConnCallResolveRoundTrip exercises the main Call/resolve flow: Call
+// sends a server-initiated request over the session's SSE channel and
+// blocks until resolve delivers a matching response.
+func TestConnCallResolveRoundTrip(t *testing.T) {
+	session := &Session{ID: "test-session"}
+	conn := newConn(session)
+
+	outgoing := make(chan interface{}, 1)
+	session.addMessageChannel(outgoing)
+
+	type callResult struct {
+		result *json.RawMessage
+		err    error
+	}
+	resultCh := make(chan callResult, 1)
+	go func() {
+		result, err := conn.Call(context.Background(), "roots/list", nil)
+		resultCh <- callResult{result, err}
+	}()
+
+	var sent Request
+	select {
+	case msg := <-outgoing:
+		sent = msg.(Request)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Call to send its request")
+	}
+
+	if sent.Method != "roots/list" {
+		t.Fatalf("sent.Method = %q, want roots/list", sent.Method)
+	}
+	idStr, ok := idString(sent.ID)
+	if !ok {
+		t.Fatalf("sent.ID = %v, not a usable id", sent.ID)
+	}
+
+	want := json.RawMessage(`{"roots":[]}`)
+	resolved := conn.resolve(&Response{
+		JSONRPC: JSONRPC{Version: "2.0", ID: idStr},
+		Result:  &want,
+	})
+	if !resolved {
+		t.Fatal("resolve reported no pending Call for the sent id")
+	}
+
+	select {
+	case cr := <-resultCh:
+		if cr.err != nil {
+			t.Fatalf("Call returned error: %v", cr.err)
+		}
+		if string(*cr.result) != string(want) {
+			t.Fatalf("Call result = %s, want %s", *cr.result, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Call to return")
+	}
+}
+
+// TestConnCloseDrainsPendingCallsWithError covers close()'s contract: every
+// Call still waiting on a response must come back with an error instead of
+// blocking forever once the owning session is torn down.
+func TestConnCloseDrainsPendingCallsWithError(t *testing.T) {
+	session := &Session{ID: "test-session"}
+	conn := newConn(session)
+	session.addMessageChannel(make(chan interface{}, 1))
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := conn.Call(context.Background(), "roots/list", nil)
+		errCh <- err
+	}()
+
+	// Give Call a moment to register itself in conn.pending before close
+	// drains it, without relying on internals.
+	time.Sleep(10 * time.Millisecond)
+	conn.close()
+
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Fatal("expected Call to return an error after close()")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Call to return after close()")
+	}
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+)
+
+// Logger is the structured logging interface the mcp package logs through.
+// Implementations can wrap zap, zerolog, slog, or anything else without the
+// mcp package importing any of them.
+type Logger interface {
+	Debug(msg string, kv ...interface{})
+	Info(msg string, kv ...interface{})
+	Warn(msg string, kv ...interface{})
+	Error(msg string, kv ...interface{})
+}
+
+type loggerContextKey struct{}
+
+func withLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, l)
+}
+
+// LoggerFromContext returns the Logger attached to ctx by AccessLogMiddleware,
+// or a no-op Logger if none was attached.
+func LoggerFromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerContextKey{}).(Logger); ok {
+		return l
+	}
+	return noopLogger{}
+}
+
+// WithLogger configures the Logger used by Server, threaded through every
+// handler via the request context so it can be swapped without the mcp
+// package depending on any particular logging library.
+func (s *Server) WithLogger(l Logger) *Server {
+	s.logger = l
+	return s
+}
+
+// stdLogger is the default Logger, backed by the standard library's log
+// package.
+type stdLogger struct {
+	*log.Logger
+}
+
+// NewStdLogger returns a Logger that writes structured key=value lines to
+// os.Stderr.
+func NewStdLogger() Logger {
+	return &stdLogger{Logger: log.New(os.Stderr, "", log.LstdFlags)}
+}
+
+func (l *stdLogger) Debug(msg string, kv ...interface{}) { l.log("DEBUG", msg, kv...) }
+func (l *stdLogger) Info(msg string, kv ...interface{})  { l.log("INFO", msg, kv...) }
+func (l *stdLogger) Warn(msg string, kv ...interface{})  { l.log("WARN", msg, kv...) }
+func (l *stdLogger) Error(msg string, kv ...interface{}) { l.log("ERROR", msg, kv...) }
+
+func (l *stdLogger) log(level, msg string, kv ...interface{}) {
+	line := level + " " + msg
+	for i := 0; i+1 < len(kv); i += 2 {
+		line += fmt.Sprintf(" %v=%v", kv[i], kv[i+1])
+	}
+	l.Println(line)
+}
+
+// noopLogger discards everything; it's the fallback returned by
+// LoggerFromContext when no Logger was attached.
+type noopLogger struct{}
+
+func (noopLogger) Debug(string, ...interface{}) {}
+func (noopLogger) Info(string, ...interface{})  {}
+func (noopLogger) Warn(string, ...interface{})  {}
+func (noopLogger) Error(string, ...interface{}) {}
@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// AccessLogMiddleware logs one line per HTTP request: method, path, resolved
+// client IP, session ID, the JSON-RPC method carried in the body (when
+// present), and the response's status code, byte count, and duration. It
+// also attaches the Server's Logger to the request context so handlers (and
+// tool calls) can log through LoggerFromContext.
+func (s *Server) AccessLogMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		r = r.WithContext(withLogger(r.Context(), s.logger))
+
+		var bodyCopy []byte
+		if r.Body != nil {
+			bodyCopy, _ = io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(bodyCopy))
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+
+		s.logger.Info("http request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"client_ip", s.clientIP(r).String(),
+			"session_id", r.Header.Get("Mcp-Session-Id"),
+			"rpc_method", peekRPCMethod(bodyCopy),
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+	}
+}
+
+func peekRPCMethod(body []byte) string {
+	var probe struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return ""
+	}
+	return probe.Method
+}
+
+// statusRecorder wraps http.ResponseWriter to capture the status code and
+// byte count written, for the access log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Flush implements http.Flusher by delegating to the wrapped
+// ResponseWriter, so SSE handlers (handleGet, HandleLegacySSE) keep working
+// when AccessLogMiddleware sits in front of them.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type contextKey string
+
+const clientIPContextKey contextKey = "mcp-client-ip"
+
+// clientIP resolves the originating client address for r. Behind a reverse
+// proxy RemoteAddr is the proxy's own address, so X-Forwarded-For / X-Real-Ip
+// are only honored once that proxy is confirmed to be trusted.
+func (s *Server) clientIP(r *http.Request) net.IP {
+	remoteIP := hostIP(r.RemoteAddr)
+
+	if !s.isTrustedProxy(remoteIP) {
+		return remoteIP
+	}
+
+	// X-Real-Ip is the highest-precedence override once the immediate peer
+	// is a trusted proxy.
+	if realIP := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-Ip"))); realIP != nil {
+		return realIP
+	}
+
+	// Walk X-Forwarded-For right-to-left: each hop appends, so the first
+	// entry (from the right) that isn't itself a trusted proxy is the real
+	// client.
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			candidate := net.ParseIP(strings.TrimSpace(hops[i]))
+			if candidate == nil || s.isTrustedProxy(candidate) {
+				continue
+			}
+			return candidate
+		}
+	}
+
+	return remoteIP
+}
+
+// hostIP strips the port from a RemoteAddr-style "host:port" string.
+func hostIP(remoteAddr string) net.IP {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+func (s *Server) isTrustedProxy(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range s.TrustedProxies {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// withClientIP attaches the resolved client IP to a request context so tool
+// handlers and logging can read it without re-deriving it.
+func withClientIP(ctx context.Context, ip net.IP) context.Context {
+	return context.WithValue(ctx, clientIPContextKey, ip)
+}
+
+// ClientIPFromContext returns the client IP resolved by AuthMiddleware, if
+// any was attached to ctx.
+func ClientIPFromContext(ctx context.Context) (net.IP, bool) {
+	ip, ok := ctx.Value(clientIPContextKey).(net.IP)
+	return ip, ok
+}
+
+// isValidOrigin reports whether the hostname of origin matches an entry of
+// AllowedOrigins (scheme and port are not compared), which supports a single
+// leading "*." glob segment (e.g. "*.example.com"). A
+// nil/empty AllowedOrigins allows any origin, preserving the permissive
+// default used for local development.
+func (s *Server) isValidOrigin(origin string) bool {
+	if len(s.AllowedOrigins) == 0 {
+		return true
+	}
+	if origin == "" {
+		return false
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+
+	for _, allowed := range s.AllowedOrigins {
+		if matchOriginHost(allowed, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchOriginHost(pattern, host string) bool {
+	if !strings.HasPrefix(pattern, "*.") {
+		return pattern == host
+	}
+	suffix := pattern[1:] // ".example.com"
+	return strings.HasSuffix(host, suffix) && host != suffix[1:]
+}
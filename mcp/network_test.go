@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func mustCIDR(t *testing.T, s string) *net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("parse CIDR %q: %v", s, err)
+	}
+	return n
+}
+
+func TestClientIPUntrustedProxyUsesRemoteAddr(t *testing.T) {
+	s := &Server{TrustedProxies: []*net.IPNet{mustCIDR(t, "10.0.0.0/8")}}
+
+	r := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+	r.RemoteAddr = "203.0.113.5:12345"
+	r.Header.Set("X-Forwarded-For", "198.51.100.9")
+
+	got := s.clientIP(r)
+	if want := net.ParseIP("203.0.113.5"); !got.Equal(want) {
+		t.Fatalf("clientIP = %v, want %v (untrusted proxy's XFF must be ignored)", got, want)
+	}
+}
+
+func TestClientIPTrustedProxyHonorsForwardedFor(t *testing.T) {
+	s := &Server{TrustedProxies: []*net.IPNet{mustCIDR(t, "10.0.0.0/8")}}
+
+	r := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+	r.RemoteAddr = "10.0.0.1:12345"
+	r.Header.Set("X-Forwarded-For", "198.51.100.9, 10.0.0.2")
+
+	got := s.clientIP(r)
+	if want := net.ParseIP("198.51.100.9"); !got.Equal(want) {
+		t.Fatalf("clientIP = %v, want %v (real client behind a chain of trusted hops)", got, want)
+	}
+}
+
+func TestIsValidOriginWildcard(t *testing.T) {
+	s := &Server{AllowedOrigins: []string{"*.example.com"}}
+
+	if !s.isValidOrigin("https://api.example.com") {
+		t.Fatalf("expected subdomain of an allowed *.example.com to be valid")
+	}
+	if s.isValidOrigin("https://example.com") {
+		t.Fatalf("expected bare example.com not to match *.example.com")
+	}
+	if s.isValidOrigin("https://evil.com") {
+		t.Fatalf("expected origin outside AllowedOrigins to be rejected")
+	}
+}
+
+func TestIsValidOriginEmptyAllowListAllowsAll(t *testing.T) {
+	s := &Server{}
+	if !s.isValidOrigin("https://anything.example") {
+		t.Fatalf("expected empty AllowedOrigins to allow any origin")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"sync"
 	"time"
@@ -13,17 +15,33 @@ import (
 
 // Server represents an MCP server instance
 type Server struct {
-	sessions       map[string]*Session
-	sessionMutex   sync.RWMutex
-	activeRequests map[string]context.CancelFunc
-	requestMutex   sync.RWMutex
+	sessions     map[string]*Session
+	sessionMutex sync.RWMutex
+	tools        *ToolRegistry
+
+	// TrustedProxies lists the CIDR ranges that are allowed to set
+	// X-Forwarded-For / X-Real-Ip on incoming requests. Leave nil to trust
+	// only RemoteAddr.
+	TrustedProxies []*net.IPNet
+
+	// AllowedOrigins lists the hosts accepted by isValidOrigin; each entry is
+	// compared against the hostname of the Origin header, so scheme and port
+	// are not checked. Entries may use a single leading "*." glob segment
+	// (e.g. "*.example.com"). A nil/empty slice allows any origin, which is
+	// the permissive default used for local development.
+	AllowedOrigins []string
+
+	authenticators []Authenticator
+	tlsConfig      *TLSConfig
+	logger         Logger
 }
 
 // NewServer creates a new MCP server instance
 func NewServer() *Server {
 	return &Server{
-		sessions:       make(map[string]*Session),
-		activeRequests: make(map[string]context.CancelFunc),
+		sessions: make(map[string]*Session),
+		tools:    newToolRegistry(),
+		logger:   NewStdLogger(),
 	}
 }
 
@@ -32,7 +50,10 @@ func (s *Server) HandleMCP(w http.ResponseWriter, r *http.Request) {
 	// Validate Origin header for security
 	origin := r.Header.Get("Origin")
 	if !s.isValidOrigin(origin) {
-		http.Error(w, "Invalid Origin", http.StatusForbidden)
+		s.sendJSONRPCError(w, http.StatusForbidden, &ErrorResponse{
+			Code:    -32000,
+			Message: "Invalid Origin",
+		})
 		return
 	}
 
@@ -49,8 +70,47 @@ func (s *Server) HandleMCP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.sendError(w, &ErrorResponse{
+			Code:    -32700,
+			Message: "Parse error",
+		})
+		return
+	}
+
+	// A batch POST body is a JSON array of requests/notifications per the
+	// JSON-RPC 2.0 spec.
+	if isBatchRequest(body) {
+		s.handleBatch(w, r, body)
+		return
+	}
+
+	// Peek at the message shape: a client POST carrying a result/error
+	// (rather than a method) is a reply to a server-initiated request made
+	// via Conn.Call, and gets routed to the matching pending channel instead
+	// of going through processRequest.
+	var envelope struct {
+		JSONRPC
+		Method *string         `json:"method"`
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		s.sendError(w, &ErrorResponse{
+			Code:    -32700,
+			Message: "Parse error",
+		})
+		return
+	}
+
+	if envelope.Method == nil && (envelope.Result != nil || envelope.Error != nil) {
+		s.handleClientResponse(w, r, body)
+		return
+	}
+
 	var request Request
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+	if err := json.Unmarshal(body, &request); err != nil {
 		s.sendError(w, &ErrorResponse{
 			Code:    -32700,
 			Message: "Parse error",
@@ -75,6 +135,160 @@ func (s *Server) handlePost(w http.ResponseWriter, r *http.Request) {
 	s.processRequest(w, r, &request)
 }
 
+// handleClientResponse routes a client reply to a server-initiated request
+// (see Conn.Call) to the session's pending channel.
+func (s *Server) handleClientResponse(w http.ResponseWriter, r *http.Request, body []byte) {
+	sessionID := r.Header.Get("Mcp-Session-Id")
+	s.sessionMutex.RLock()
+	session, exists := s.sessions[sessionID]
+	s.sessionMutex.RUnlock()
+	if !exists || session.Conn == nil {
+		http.Error(w, "Invalid session", http.StatusNotFound)
+		return
+	}
+
+	var resp Response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		s.sendError(w, &ErrorResponse{
+			Code:    -32700,
+			Message: "Parse error",
+		})
+		return
+	}
+
+	session.Conn.resolve(&resp)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// isBatchRequest reports whether the POST body is a JSON-RPC batch, i.e. a
+// JSON array rather than a single object.
+func isBatchRequest(body []byte) bool {
+	for _, b := range body {
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '[':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// handleBatch processes a JSON-RPC batch: a JSON array of requests and/or
+// notifications. Each element is dispatched concurrently; responses are
+// collected back into the original order, notifications are omitted, and
+// the remaining responses are always written back as a JSON array, even when
+// only one is left. A batch made up only of notifications gets 204 No Content
+// with no body.
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request, body []byte) {
+	var rawItems []json.RawMessage
+	if err := json.Unmarshal(body, &rawItems); err != nil {
+		s.sendError(w, &ErrorResponse{
+			Code:    -32700,
+			Message: "Parse error",
+		})
+		return
+	}
+
+	if len(rawItems) == 0 {
+		s.sendError(w, &ErrorResponse{
+			Code:    -32600,
+			Message: "Invalid Request",
+		})
+		return
+	}
+
+	sessionID := r.Header.Get("Mcp-Session-Id")
+	s.sessionMutex.RLock()
+	session, sessionOK := s.sessions[sessionID]
+	s.sessionMutex.RUnlock()
+
+	responses := make([]*Response, len(rawItems))
+	var wg sync.WaitGroup
+
+	for i, raw := range rawItems {
+		i, raw := i, raw
+
+		var probe struct {
+			JSONRPC
+			Method *string `json:"method"`
+		}
+		if err := json.Unmarshal(raw, &probe); err != nil || probe.Method == nil {
+			responses[i] = &Response{
+				JSONRPC: JSONRPC{Version: "2.0"},
+				Error:   &ErrorResponse{Code: -32600, Message: "Invalid Request"},
+			}
+			continue
+		}
+
+		var req Request
+		if err := json.Unmarshal(raw, &req); err != nil {
+			responses[i] = &Response{
+				JSONRPC: JSONRPC{Version: "2.0"},
+				Error:   &ErrorResponse{Code: -32600, Message: "Invalid Request"},
+			}
+			continue
+		}
+
+		if req.Method == "initialize" {
+			responses[i] = &Response{
+				JSONRPC: JSONRPC{Version: "2.0", ID: req.ID},
+				Error:   &ErrorResponse{Code: -32600, Message: "initialize is not allowed in a batch"},
+			}
+			continue
+		}
+
+		if !sessionOK {
+			responses[i] = &Response{
+				JSONRPC: JSONRPC{Version: "2.0", ID: req.ID},
+				Error:   &ErrorResponse{Code: -32600, Message: "Invalid session"},
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+			if idStr, ok := idString(req.ID); ok && session.Conn != nil {
+				session.Conn.trackHandling(idStr, cancel)
+				defer session.Conn.stopHandling(idStr)
+			}
+
+			resp, isNotification := s.computeResponse(ctx, session, &req)
+			if isNotification {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+			default:
+				responses[i] = &resp
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var ordered []Response
+	for _, resp := range responses {
+		if resp != nil {
+			ordered = append(ordered, *resp)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(ordered) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	json.NewEncoder(w).Encode(ordered)
+}
+
 func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
 	// Set headers for SSE
 	w.Header().Set("Content-Type", "text/event-stream")
@@ -103,25 +317,14 @@ func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
 	messageChan := make(chan interface{}, 10)
 
 	// Register client's message channel
-	s.sessionMutex.Lock()
+	s.sessionMutex.RLock()
 	session := s.sessions[sessionID]
-	if session.MessageChannels == nil {
-		session.MessageChannels = make([]chan interface{}, 0)
-	}
-	session.MessageChannels = append(session.MessageChannels, messageChan)
-	s.sessionMutex.Unlock()
+	s.sessionMutex.RUnlock()
+	session.addMessageChannel(messageChan)
 
 	// Cleanup function
 	defer func() {
-		s.sessionMutex.Lock()
-		// Remove the message channel from the session
-		for i, ch := range session.MessageChannels {
-			if ch == messageChan {
-				session.MessageChannels = append(session.MessageChannels[:i], session.MessageChannels[i+1:]...)
-				break
-			}
-		}
-		s.sessionMutex.Unlock()
+		session.removeMessageChannel(messageChan)
 		close(messageChan)
 	}()
 
@@ -155,10 +358,14 @@ func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.Header.Get("Mcp-Session-Id")
-	if s.validateSession(sessionID) {
-		s.sessionMutex.Lock()
+	s.sessionMutex.Lock()
+	session, exists := s.sessions[sessionID]
+	if exists {
 		delete(s.sessions, sessionID)
-		s.sessionMutex.Unlock()
+	}
+	s.sessionMutex.Unlock()
+	if exists && session.Conn != nil {
+		session.Conn.close()
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -174,6 +381,7 @@ func (s *Server) handleInitialize(w http.ResponseWriter, r *http.Request, req *R
 			},
 		},
 	}
+	session.Conn = newConn(session)
 
 	s.sessionMutex.Lock()
 	s.sessions[sessionID] = session
@@ -202,49 +410,56 @@ func (s *Server) validateSession(sessionID string) bool {
 	return exists
 }
 
-func (s *Server) isValidOrigin(origin string) bool {
-	// Implement origin validation logic
-	// For development, you might want to allow localhost
-	return true
-}
-
 func (s *Server) processRequest(w http.ResponseWriter, r *http.Request, req *Request) {
-	// Create a cancellable context for this request
-	//	ctx, cancel := context.WithCancel(r.Context())
-	//	defer cancel()
+	sessionID := r.Header.Get("Mcp-Session-Id")
+	s.sessionMutex.RLock()
+	session := s.sessions[sessionID]
+	s.sessionMutex.RUnlock()
 
-	// Store the cancel function
-	//	s.requestMutex.Lock()
-	//	s.activeRequests[req.ID.(string)] = cancel
-	//	s.requestMutex.Unlock()
+	// Create a cancellable context for this request and register it with the
+	// session's Conn so a notifications/cancelled for this ID actually stops
+	// the in-flight handler.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
 
-	// Clean up the request when done
-	//	defer func() {
-	//		s.requestMutex.Lock()
-	//		delete(s.activeRequests, req.ID.(string))
-	//		s.requestMutex.Unlock()
-	//	}()
+	if idStr, ok := idString(req.ID); ok && session != nil && session.Conn != nil {
+		session.Conn.trackHandling(idStr, cancel)
+		defer session.Conn.stopHandling(idStr)
+	}
 
-	var response Response
+	response, isNotification := s.computeResponse(ctx, session, req)
+	if isNotification {
+		return
+	}
 
-	// Handle other requests as before
+	// Check if the request was cancelled before sending the response.
+	select {
+	case <-ctx.Done():
+		return
+	default:
+		s.sendResponse(w, response)
+	}
+}
+
+// computeResponse dispatches a single request to its handler and returns the
+// response to send, or isNotification=true if req has no ID and therefore
+// expects no reply, per the JSON-RPC 2.0 spec. Shared by the single-request
+// and batch code paths.
+func (s *Server) computeResponse(ctx context.Context, session *Session, req *Request) (response Response, isNotification bool) {
 	switch req.Method {
 	case "notifications/cancelled":
 		var cancelParams CancellationParams
-		if err := json.Unmarshal(req.Params, &cancelParams); err == nil {
-			s.handleCancellation(&CancellationNotification{
-				Version: req.Version,
-				Method:  req.Method,
-				Params:  cancelParams,
-			})
-			return
+		if err := json.Unmarshal(req.Params, &cancelParams); err == nil && session != nil && session.Conn != nil {
+			if idStr, ok := idString(cancelParams.RequestID); ok {
+				session.Conn.cancelHandling(idStr)
+			}
 		}
 	case "ping":
 		response = s.handlePing(req)
 	case "tools/list":
 		response = s.handleToolsList(req)
 	case "tools/call":
-		response = s.handleToolCall(req)
+		response = s.handleToolCall(ctx, req)
 	default:
 		response = Response{
 			JSONRPC: JSONRPC{
@@ -258,16 +473,10 @@ func (s *Server) processRequest(w http.ResponseWriter, r *http.Request, req *Req
 		}
 	}
 
-	// add error hndler?
-
-	// Check if the request was cancelled before sending response
-	//	select {
-	//		case <-ctx.Done():
-	//		// Request was cancelled, don't send response
-	//		return
-	//	default:
-	s.sendResponse(w, response)
-	//	}
+	if req.ID == nil {
+		return Response{}, true
+	}
+	return response, false
 }
 
 func (s *Server) sendError(w http.ResponseWriter, err *ErrorResponse) {
@@ -278,6 +487,18 @@ func (s *Server) sendError(w http.ResponseWriter, err *ErrorResponse) {
 	})
 }
 
+// sendJSONRPCError writes a JSON-RPC error object with a non-200 HTTP status,
+// for failures (invalid origin, auth) that happen before a request is even
+// parsed but still deserve a structured body instead of plain-text.
+func (s *Server) sendJSONRPCError(w http.ResponseWriter, statusCode int, err *ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(Response{
+		JSONRPC: JSONRPC{Version: "2.0"},
+		Error:   err,
+	})
+}
+
 func (s *Server) sendResponse(w http.ResponseWriter, resp Response) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
@@ -293,39 +514,11 @@ func (s *Server) marshalJSON(v interface{}) *json.RawMessage {
 func (s *Server) broadcastToSession(sessionID string, message interface{}) {
 	s.sessionMutex.RLock()
 	session, exists := s.sessions[sessionID]
+	s.sessionMutex.RUnlock()
 	if !exists {
-		s.sessionMutex.RUnlock()
 		return
 	}
-
-	for _, ch := range session.MessageChannels {
-		select {
-		case ch <- message:
-			// Message sent successfully
-		default:
-			// Channel is full, skip this client
-		}
-	}
-	s.sessionMutex.RUnlock()
-}
-
-// Add authentication middleware
-func (s *Server) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		token := r.Header.Get("X-API-Key")
-		if !s.validateToken(token) {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-		next(w, r)
-	}
-}
-
-// validateToken checks if the provided API key is valid
-func (s *Server) validateToken(token string) bool {
-	// For development, accept any non-empty token
-	// In production, implement proper token validation
-	return true //token != ""
+	session.broadcast(message)
 }
 
 // HandleLegacySSE handles the legacy HTTP+SSE transport
@@ -358,6 +551,7 @@ func (s *Server) HandleLegacySSE(w http.ResponseWriter, r *http.Request) {
 		ID:              uuid.New().String(),
 		MessageChannels: []chan interface{}{messageChan},
 	}
+	session.Conn = newConn(session)
 	s.sessions[session.ID] = session
 	s.sessionMutex.Unlock()
 
@@ -366,6 +560,7 @@ func (s *Server) HandleLegacySSE(w http.ResponseWriter, r *http.Request) {
 		s.sessionMutex.Lock()
 		delete(s.sessions, session.ID)
 		s.sessionMutex.Unlock()
+		session.Conn.close()
 		close(messageChan)
 	}()
 
@@ -403,22 +598,3 @@ func (s *Server) handlePing(req *Request) Response {
 		Result: s.marshalJSON(struct{}{}), // Empty result as per spec
 	}
 }
-
-// handleCancellation handles cancellation notifications
-func (s *Server) handleCancellation(notification *CancellationNotification) {
-	s.requestMutex.Lock()
-	defer s.requestMutex.Unlock()
-
-	// Get the cancel function for this request
-	cancel, exists := s.activeRequests[notification.Params.RequestID]
-	if !exists {
-		// Request not found or already completed
-		return
-	}
-
-	// Cancel the request
-	cancel()
-
-	// Remove from active requests
-	delete(s.activeRequests, notification.Params.RequestID)
-}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestHandleBatchPreservesOrderAndFiltersNotifications dispatches a batch
+// with a mix of requests and a notification. Each item runs in its own
+// goroutine, so this also guards against a flaky/racy implementation
+// reordering or dropping responses.
+func TestHandleBatchPreservesOrderAndFiltersNotifications(t *testing.T) {
+	s := NewServer()
+	session := &Session{ID: "test-session"}
+	session.Conn = newConn(session)
+	s.sessions[session.ID] = session
+
+	body, err := json.Marshal([]map[string]interface{}{
+		{"jsonrpc": "2.0", "id": 1, "method": "ping"},
+		{"jsonrpc": "2.0", "method": "notifications/cancelled", "params": map[string]interface{}{"requestId": 999}},
+		{"jsonrpc": "2.0", "id": 2, "method": "ping"},
+	})
+	if err != nil {
+		t.Fatalf("marshal batch: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(body))
+	r.Header.Set("Mcp-Session-Id", session.ID)
+	w := httptest.NewRecorder()
+
+	s.handleBatch(w, r, body)
+
+	var responses []Response
+	if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("unmarshal responses: %v (body: %s)", err, w.Body.String())
+	}
+
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses (notification filtered out), got %d", len(responses))
+	}
+	if idStr, _ := idString(responses[0].ID); idStr != "1" {
+		t.Errorf("responses[0].ID = %v, want 1", responses[0].ID)
+	}
+	if idStr, _ := idString(responses[1].ID); idStr != "2" {
+		t.Errorf("responses[1].ID = %v, want 2", responses[1].ID)
+	}
+}
+
+// TestHandleBatchFiltersUnrecognizedNotification covers a notification whose
+// method isn't specifically handled (e.g. the standard MCP
+// notifications/initialized): what makes it a notification is the absent
+// "id" field, not the method name, so it must be omitted from the batch
+// response rather than falling through to a method-not-found error.
+func TestHandleBatchFiltersUnrecognizedNotification(t *testing.T) {
+	s := NewServer()
+	session := &Session{ID: "test-session"}
+	session.Conn = newConn(session)
+	s.sessions[session.ID] = session
+
+	body, err := json.Marshal([]map[string]interface{}{
+		{"jsonrpc": "2.0", "method": "notifications/initialized"},
+		{"jsonrpc": "2.0", "id": 1, "method": "ping"},
+	})
+	if err != nil {
+		t.Fatalf("marshal batch: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(body))
+	r.Header.Set("Mcp-Session-Id", session.ID)
+	w := httptest.NewRecorder()
+
+	s.handleBatch(w, r, body)
+
+	var responses []Response
+	if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("unmarshal responses: %v (body: %s)", err, w.Body.String())
+	}
+
+	if len(responses) != 1 {
+		t.Fatalf("expected 1 response (notification filtered out), got %d: %+v", len(responses), responses)
+	}
+	if idStr, _ := idString(responses[0].ID); idStr != "1" {
+		t.Errorf("responses[0].ID = %v, want 1", responses[0].ID)
+	}
+}
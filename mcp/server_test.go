@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestComputeResponseCancelsNumericRequestID covers a client cancelling a
+// request whose original ID was a JSON number, e.g. {"requestId": 5, ...}.
+// CancellationParams.RequestID must accept that via idString normalization
+// rather than failing to unmarshal into a string field.
+func TestComputeResponseCancelsNumericRequestID(t *testing.T) {
+	s := NewServer()
+	session := &Session{ID: "test-session"}
+	session.Conn = newConn(session)
+
+	cancelled := false
+	_, cancel := context.WithCancel(context.Background())
+	session.Conn.trackHandling("5", func() { cancelled = true; cancel() })
+
+	params, err := json.Marshal(map[string]interface{}{"requestId": 5})
+	if err != nil {
+		t.Fatalf("marshal params: %v", err)
+	}
+
+	req := &Request{
+		JSONRPC: JSONRPC{Version: "2.0"},
+		Method:  "notifications/cancelled",
+		Params:  params,
+	}
+
+	_, isNotification := s.computeResponse(context.Background(), session, req)
+	if !isNotification {
+		t.Fatalf("expected notifications/cancelled to report isNotification=true")
+	}
+	if !cancelled {
+		t.Fatalf("expected numeric requestId 5 to cancel the tracked handler")
+	}
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfig configures the transport layer's TLS listener: server
+// certificate and key, an optional client CA bundle, and the client
+// certificate policy to enforce.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+
+	// ClientAuthType is one of "none", "request", "require", "verify", or
+	// "verify+require". Anything else is treated as "none".
+	ClientAuthType string
+}
+
+// GetAuthType maps ClientAuthType to the corresponding tls.ClientAuthType,
+// defaulting to tls.NoClientCert.
+func (c *TLSConfig) GetAuthType() tls.ClientAuthType {
+	switch c.ClientAuthType {
+	case "request":
+		return tls.RequestClientCert
+	case "require":
+		return tls.RequireAnyClientCert
+	case "verify":
+		return tls.VerifyClientCertIfGiven
+	case "verify+require":
+		return tls.RequireAndVerifyClientCert
+	default:
+		return tls.NoClientCert
+	}
+}
+
+// GetTLSConfig builds a *tls.Config from the certificate, key, and optional
+// client CA bundle described by c.
+func (c *TLSConfig) GetTLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load server certificate: %w", err)
+	}
+
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   c.GetAuthType(),
+	}
+
+	if c.CAFile != "" {
+		caPEM, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+		cfg.ClientCAs = pool
+	}
+
+	return cfg, nil
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 )
 
 // Tool represents an MCP tool definition
@@ -31,30 +34,106 @@ type ToolResult struct {
 	IsError bool          `json:"isError"`
 }
 
-// WeatherArgs represents the input arguments for the get_weather tool
-type WeatherArgs struct {
-	Location string `json:"location"`
+// ToolHandler is implemented by anything that can be registered as an MCP
+// tool: it describes itself for tools/list and executes tools/call
+// invocations. Consumers of the mcp package register their own handlers via
+// Server.RegisterTool instead of editing the library.
+type ToolHandler interface {
+	Definition() Tool
+	Invoke(ctx context.Context, args json.RawMessage) (ToolResult, error)
 }
 
-// weatherTool is our sample weather tool implementation
-var weatherTool = Tool{
-	Name:        "get_weather",
-	Description: "Get current weather information for a location",
-	InputSchema: json.RawMessage(`{
-		"type": "object",
-		"properties": {
-			"location": {
-				"type": "string",
-				"description": "City name or zip code"
-			}
-		},
-		"required": ["location"]
-	}`),
+// ToolRegistry holds the set of tools currently exposed by a Server.
+type ToolRegistry struct {
+	mu    sync.RWMutex
+	tools map[string]ToolHandler
+}
+
+// newToolRegistry creates an empty ToolRegistry.
+func newToolRegistry() *ToolRegistry {
+	return &ToolRegistry{tools: make(map[string]ToolHandler)}
+}
+
+func (reg *ToolRegistry) register(handler ToolHandler) {
+	reg.mu.Lock()
+	reg.tools[handler.Definition().Name] = handler
+	reg.mu.Unlock()
+}
+
+func (reg *ToolRegistry) unregister(name string) {
+	reg.mu.Lock()
+	delete(reg.tools, name)
+	reg.mu.Unlock()
+}
+
+func (reg *ToolRegistry) get(name string) (ToolHandler, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	handler, ok := reg.tools[name]
+	return handler, ok
+}
+
+// list returns the registered tool definitions sorted by name, for a stable
+// tools/list ordering.
+func (reg *ToolRegistry) list() []Tool {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(reg.tools))
+	for _, handler := range reg.tools {
+		tools = append(tools, handler.Definition())
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// RegisterTool adds a tool to the registry, making it available via
+// tools/list and tools/call, and notifies every session whose Capabilities
+// have tools.listChanged set to true.
+func (s *Server) RegisterTool(handler ToolHandler) {
+	s.tools.register(handler)
+	s.notifyToolsListChanged()
+}
+
+// UnregisterTool removes a tool from the registry by name and notifies every
+// session whose Capabilities have tools.listChanged set to true.
+func (s *Server) UnregisterTool(name string) {
+	s.tools.unregister(name)
+	s.notifyToolsListChanged()
+}
+
+// ListTools returns the currently registered tool definitions.
+func (s *Server) ListTools() []Tool {
+	return s.tools.list()
+}
+
+// notifyToolsListChanged broadcasts notifications/tools/list_changed to
+// every session whose Capabilities have tools.listChanged set to true.
+func (s *Server) notifyToolsListChanged() {
+	s.sessionMutex.RLock()
+	ids := make([]string, 0, len(s.sessions))
+	for id, session := range s.sessions {
+		if toolsListChangedEnabled(session) {
+			ids = append(ids, id)
+		}
+	}
+	s.sessionMutex.RUnlock()
+
+	for _, id := range ids {
+		s.broadcastToSession(id, Notification{
+			Version: "2.0",
+			Method:  "notifications/tools/list_changed",
+		})
+	}
 }
 
-// getAvailableTools returns the list of available tools
-func (s *Server) getAvailableTools() []Tool {
-	return []Tool{weatherTool}
+func toolsListChangedEnabled(session *Session) bool {
+	tools, ok := session.Capabilities["tools"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	listChanged, _ := tools["listChanged"].(bool)
+	return listChanged
 }
 
 // handleToolsList handles the tools/list request
@@ -65,13 +144,13 @@ func (s *Server) handleToolsList(req *Request) Response {
 			ID:      req.ID,
 		},
 		Result: s.marshalJSON(map[string]interface{}{
-			"tools": s.getAvailableTools(),
+			"tools": s.ListTools(),
 		}),
 	}
 }
 
 // handleToolCall handles the tools/call request
-func (s *Server) handleToolCall(req *Request) Response {
+func (s *Server) handleToolCall(ctx context.Context, req *Request) Response {
 	var toolReq ToolCallRequest
 	if err := json.Unmarshal(req.Params, &toolReq); err != nil {
 		return Response{
@@ -86,82 +165,37 @@ func (s *Server) handleToolCall(req *Request) Response {
 		}
 	}
 
-	// Handle get_weather tool
-	if toolReq.Name == "get_weather" {
-		//	return s.handleWeatherTool(req.Context(), req.ID, toolReq.Arguments)
-		return s.handleWeatherTool(context.Background(), req.ID, toolReq.Arguments)
-	}
-
-	return Response{
-		JSONRPC: JSONRPC{
-			Version: "2.0",
-			ID:      req.ID,
-		},
-		Error: &ErrorResponse{
-			Code:    -32602,
-			Message: fmt.Sprintf("Unknown tool: %s", toolReq.Name),
-		},
-	}
-}
-
-// handleWeatherTool handles the get_weather tool execution
-func (s *Server) handleWeatherTool(ctx context.Context, reqID any, args json.RawMessage) Response {
-	var weatherArgs WeatherArgs
-	if err := json.Unmarshal(args, &weatherArgs); err != nil {
+	handler, ok := s.tools.get(toolReq.Name)
+	if !ok {
 		return Response{
 			JSONRPC: JSONRPC{
 				Version: "2.0",
-				ID:      reqID,
+				ID:      req.ID,
+			},
+			Error: &ErrorResponse{
+				Code:    -32602,
+				Message: fmt.Sprintf("Unknown tool: %s", toolReq.Name),
 			},
-			Result: s.marshalJSON(ToolResult{
-				Content: []ContentItem{
-					{
-						Type: "text",
-						Text: "Failed to parse weather arguments",
-					},
-				},
-				IsError: true,
-			}),
 		}
 	}
 
-	// Simulate a long-running operation that can be cancelled
-	// select {
-	// case <-ctx.Done():
-	// Request was cancelled
-	//	return Response{
-	//		JSONRPC: JSONRPC{
-	//			Version: "2.0",
-	//			ID:      reqID,
-	//		},
-	//		Result: s.marshalJSON(ToolResult{
-	//			Content: []ContentItem{
-	//				{
-	//					Type: "text",
-	//					Text: "Weather request cancelled",
-	//				},
-	//			},
-	//			IsError: true,
-	//		}),
-	//	}
-	// case <-time.After(2 * time.Second): // Simulate API delay
-	// Return mock weather data
-	result := ToolResult{
-		Content: []ContentItem{
-			{
-				Type: "text",
-				Text: fmt.Sprintf("Current weather in %s:\nTemperature: 72°F\nConditions: Partly cloudy", weatherArgs.Location),
+	start := time.Now()
+	result, err := handler.Invoke(ctx, toolReq.Arguments)
+	LoggerFromContext(ctx).Info("tool call", "tool", toolReq.Name, "duration_ms", time.Since(start).Milliseconds())
+	if err != nil {
+		result = ToolResult{
+			Content: []ContentItem{
+				{Type: "text", Text: err.Error()},
 			},
-		},
-		IsError: false,
+			IsError: true,
+		}
 	}
 
 	return Response{
 		JSONRPC: JSONRPC{
 			Version: "2.0",
-			ID:      reqID,
+			ID:      req.ID,
 		},
 		Result: s.marshalJSON(result),
 	}
-
 }
@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// WithTLS configures the HTTPS listener started by ListenAndServe. Passing
+// nil disables the HTTPS listener.
+func (s *Server) WithTLS(cfg *TLSConfig) *Server {
+	s.tlsConfig = cfg
+	return s
+}
+
+// ListenAndServe starts an HTTP listener on httpAddr and, when WithTLS has
+// configured a TLSConfig, an HTTPS listener on httpsAddr, both serving
+// handler. It blocks until either listener returns an error.
+func (s *Server) ListenAndServe(httpAddr, httpsAddr string, handler http.Handler) error {
+	errCh := make(chan error, 2)
+
+	go func() {
+		errCh <- http.ListenAndServe(httpAddr, handler)
+	}()
+
+	if s.tlsConfig != nil {
+		tlsCfg, err := s.tlsConfig.GetTLSConfig()
+		if err != nil {
+			return fmt.Errorf("configure TLS: %w", err)
+		}
+
+		httpsServer := &http.Server{
+			Addr:      httpsAddr,
+			Handler:   handler,
+			TLSConfig: tlsCfg,
+		}
+
+		go func() {
+			errCh <- httpsServer.ListenAndServeTLS("", "")
+		}()
+	}
+
+	return <-errCh
+}
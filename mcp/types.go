@@ -1,6 +1,9 @@
 package mcp
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"sync"
+)
 
 // JSONRPC represents the base JSON-RPC 2.0 message structure
 type JSONRPC struct {
@@ -38,15 +41,54 @@ type Notification struct {
 
 // Session represents an MCP session
 type Session struct {
-	ID              string
-	Capabilities    map[string]interface{}
+	ID           string
+	Capabilities map[string]interface{}
+	Conn         *Conn
+
+	channelsMu      sync.Mutex
 	MessageChannels []chan interface{}
 	// Add other session-specific data as needed
 }
 
+// addMessageChannel registers ch as an SSE destination for the session.
+func (sess *Session) addMessageChannel(ch chan interface{}) {
+	sess.channelsMu.Lock()
+	sess.MessageChannels = append(sess.MessageChannels, ch)
+	sess.channelsMu.Unlock()
+}
+
+// removeMessageChannel unregisters ch, e.g. once its SSE stream disconnects.
+func (sess *Session) removeMessageChannel(ch chan interface{}) {
+	sess.channelsMu.Lock()
+	for i, c := range sess.MessageChannels {
+		if c == ch {
+			sess.MessageChannels = append(sess.MessageChannels[:i], sess.MessageChannels[i+1:]...)
+			break
+		}
+	}
+	sess.channelsMu.Unlock()
+}
+
+// broadcast pushes message onto every channel currently registered for the
+// session, skipping any that are full rather than blocking.
+func (sess *Session) broadcast(message interface{}) {
+	sess.channelsMu.Lock()
+	defer sess.channelsMu.Unlock()
+	for _, ch := range sess.MessageChannels {
+		select {
+		case ch <- message:
+		default:
+			// Channel is full, skip this client.
+		}
+	}
+}
+
 // CancellationParams represents the parameters for a cancellation notification
 type CancellationParams struct {
-	RequestID string `json:"requestId"`
+	// RequestID is any, not string, because request IDs can be numeric
+	// (encoding/json decodes them as float64); normalize with idString
+	// before using it as a map key.
+	RequestID any    `json:"requestId"`
 	Reason    string `json:"reason,omitempty"`
 }
 